@@ -0,0 +1,34 @@
+// Package beacon defines the randomness source used to make zbft leader
+// election unpredictable to an adversary who can otherwise grind future
+// proposer slots by controlling only on-chain state.
+package beacon
+
+import "errors"
+
+// ErrNoEntry is returned by an Entry implementation when no entry is yet
+// available for the requested round.
+var ErrNoEntry = errors.New("beacon: no entry available for round")
+
+// RandomnessBeacon supplies verifiable per-round entropy used, alongside the
+// validator set, to compute the proposer for a round.
+type RandomnessBeacon interface {
+	// Entry returns the randomness for round. Implementations should block
+	// or return ErrNoEntry if the entry is not yet available.
+	Entry(round uint64) ([]byte, error)
+	// Verify checks that cur is a valid entry for round, and a valid
+	// successor to prev, as produced by this beacon. prev is nil for the
+	// first round a node verifies.
+	Verify(round uint64, prev, cur []byte) error
+}
+
+// NopBeacon is the default RandomnessBeacon, preserving the previous
+// behavior of deriving the proposer purely from on-chain validator state.
+// Entry always returns a nil, deterministic entry and Verify always
+// succeeds.
+type NopBeacon struct{}
+
+// Entry always returns a nil entry and no error.
+func (NopBeacon) Entry(round uint64) ([]byte, error) { return nil, nil }
+
+// Verify always succeeds.
+func (NopBeacon) Verify(round uint64, prev, cur []byte) error { return nil }
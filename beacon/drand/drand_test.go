@@ -0,0 +1,76 @@
+package drand
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEntryDecodesHexAndChecksRandomness(t *testing.T) {
+	sig := []byte("a-fake-bls-signature")
+	sum := sha256.Sum256(sig)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"round":1,"randomness":"%s","signature":"%s"}`,
+			hex.EncodeToString(sum[:]), hex.EncodeToString(sig))
+	}))
+	defer srv.Close()
+
+	b := New(srv.URL)
+
+	entry, err := b.Entry(1)
+	if err != nil {
+		t.Fatalf("Entry returned error: %v", err)
+	}
+
+	if string(entry) != string(sig) {
+		t.Fatalf("expected Entry to return the decoded signature %q, got %q", sig, entry)
+	}
+}
+
+func TestEntryRejectsMismatchedRandomness(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"round":1,"randomness":"aabb","signature":"ccdd"}`)
+	}))
+	defer srv.Close()
+
+	b := New(srv.URL)
+
+	if _, err := b.Entry(1); err == nil {
+		t.Fatal("expected Entry to reject randomness that isn't sha256(signature)")
+	}
+}
+
+func TestVerifyFailsClosedWithoutVerifier(t *testing.T) {
+	b := New("https://example.invalid")
+
+	if err := b.Verify(1, nil, []byte("cur")); err == nil {
+		t.Fatal("expected Verify to fail closed when no Verifier/PublicKey is configured")
+	}
+}
+
+type fakeVerifier struct {
+	err error
+}
+
+func (f fakeVerifier) Verify(pubKey, msg, sig []byte) error {
+	return f.err
+}
+
+func TestVerifyDelegatesToConfiguredVerifier(t *testing.T) {
+	b := New("https://example.invalid")
+	b.PublicKey = []byte("pubkey")
+	b.Verifier = fakeVerifier{}
+
+	if err := b.Verify(1, []byte("prev"), []byte("cur")); err != nil {
+		t.Fatalf("expected Verify to succeed, got: %v", err)
+	}
+
+	b.Verifier = fakeVerifier{err: fmt.Errorf("bad signature")}
+	if err := b.Verify(1, []byte("prev"), []byte("cur")); err == nil {
+		t.Fatal("expected Verify to propagate the Verifier's error")
+	}
+}
@@ -0,0 +1,87 @@
+package zbft
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/hexablock/blockchain/bcpb"
+)
+
+// defaultTimePerBlock is how often the leader proposes a new block absent
+// any other policy, mirroring neo-go's defaultTimePerBlock.
+const defaultTimePerBlock = 15 * time.Second
+
+// defaultMaxBlockSize and defaultMaxTxsPerBlock bound the block the leader
+// assembles from the mempool when no override is configured.
+const (
+	defaultMaxBlockSize   = 1 << 20 // 1MiB
+	defaultMaxTxsPerBlock = 500
+)
+
+// EmptyBlockPolicy controls whether the leader proposes a block when the
+// mempool has no verified transactions to include.
+type EmptyBlockPolicy int
+
+const (
+	// EmptyBlockOnDemand is the zero value and the default: when TimePerBlock
+	// elapses with no txs handed to it via the tx-ready path, it re-checks
+	// the mempool itself before giving up and proposing empty, so a
+	// transaction the mempool admitted but hadn't yet been dispatched isn't
+	// needlessly left out of the block. It only actually proposes empty
+	// when the mempool really has nothing. Being the zero value means a
+	// zero Config.EmptyBlockPolicy gets this default rather than silently
+	// behaving like EmptyBlockNever.
+	EmptyBlockOnDemand EmptyBlockPolicy = iota
+	// EmptyBlockNever never proposes a block unless the mempool has at
+	// least one verified transaction.
+	EmptyBlockNever
+	// EmptyBlockAlways proposes an empty block every TimePerBlock tick
+	// without EmptyBlockOnDemand's mempool re-check, regardless of whether
+	// the mempool has pending transactions.
+	EmptyBlockAlways
+)
+
+// handleReadyTxs is invoked by Start's consensus loop with the next batch
+// of mempool-verified transactions the leader has ready, or with a nil txs
+// and timerFired set whenever blockTimer fires because TimePerBlock has
+// elapsed since the last proposal. timerFired is what EmptyBlockPolicy
+// branches on for an empty batch, not len(txs)==0: a zero-tx batch can also
+// arrive because ProposeTxs admitted nothing (e.g. every tx was rejected by
+// a mempool policy), and that shouldn't be treated as "TimePerBlock has
+// elapsed" just because it happens to be empty too. A non-empty batch is
+// always proposed regardless of policy or timerFired. proposeBlock resolves
+// whether this node is actually the proposer for the current round.
+func (z *zbft) handleReadyTxs(txs []*bcpb.Tx, timerFired bool) {
+	if timerFired {
+		// Only the timer path drains blockTimer.C, so only it needs to
+		// re-arm the timer. Resetting on every tx-ready call too would let
+		// a steady trickle of empty-admission ProposeTxs calls keep pushing
+		// the deadline out, starving the keep-alive empty block.
+		z.blockTimer.Reset(time.Duration(atomic.LoadInt64(&z.timePerBlock)))
+	}
+
+	if len(txs) == 0 {
+		if !timerFired {
+			// Nothing admitted by ProposeTxs and TimePerBlock hasn't
+			// elapsed; there's nothing to propose yet.
+			return
+		}
+
+		switch EmptyBlockPolicy(atomic.LoadInt32(&z.emptyBlockPolicy)) {
+		case EmptyBlockNever:
+			return
+		case EmptyBlockOnDemand:
+			maxTxsPerBlock := int(atomic.LoadInt64(&z.maxTxsPerBlock))
+			maxBlockSize := int(atomic.LoadInt64(&z.maxBlockSize))
+			if pending := boundTxsBySize(z.mempool.GetVerified(maxTxsPerBlock), maxBlockSize); len(pending) > 0 {
+				txs = pending
+			}
+			// else: mempool really has nothing; propose empty below.
+		case EmptyBlockAlways:
+			// TimePerBlock elapsed; propose an empty block to keep the
+			// chain live without EmptyBlockOnDemand's mempool re-check.
+		}
+	}
+
+	z.proposeBlock(txs)
+}
@@ -0,0 +1,34 @@
+package zbft
+
+import (
+	"testing"
+
+	"github.com/hexablock/blockchain/bcpb"
+)
+
+func TestEmptyBlockPolicyZeroValueIsOnDemand(t *testing.T) {
+	var p EmptyBlockPolicy
+
+	if p != EmptyBlockOnDemand {
+		t.Fatalf("expected the zero value of EmptyBlockPolicy to be EmptyBlockOnDemand, got %v", p)
+	}
+}
+
+func TestBoundTxsBySize(t *testing.T) {
+	txs := []*bcpb.Tx{
+		{Hash: []byte("12345")},
+		{Hash: []byte("12345")},
+		{Hash: []byte("12345")},
+	}
+
+	txSize := txs[0].Size()
+
+	bounded := boundTxsBySize(txs, 2*txSize)
+	if len(bounded) != 2 {
+		t.Fatalf("expected 2 txs to fit under a %d byte cap, got %d", 2*txSize, len(bounded))
+	}
+
+	if bounded = boundTxsBySize(txs, 0); len(bounded) != len(txs) {
+		t.Fatalf("expected a non-positive maxSize to disable the cap, got %d txs", len(bounded))
+	}
+}
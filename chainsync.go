@@ -0,0 +1,19 @@
+package zbft
+
+// ChainSync reports whether the local node has caught up to the network's
+// chain tip. Ready consults it alongside Start having begun its consensus
+// loop, so a node that is still syncing historical blocks doesn't start
+// accepting live consensus traffic via StepContext.
+type ChainSync interface {
+	// Synced reports whether the local chain has caught up to the tip.
+	Synced() bool
+}
+
+// nopChainSync is the default ChainSync. It always reports synced, which
+// reproduces the previous behavior where Ready only gated on Start having
+// begun its consensus loop: this tree has no chain-tip tracking of its own
+// to drive a real answer from. Configure Config.ChainSync with a real
+// implementation when integrating with a client that can report sync state.
+type nopChainSync struct{}
+
+func (nopChainSync) Synced() bool { return true }
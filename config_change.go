@@ -0,0 +1,51 @@
+package zbft
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// configChangeType identifies the kind of runtime configuration change sent
+// over a zbft instance's confCh.
+type configChangeType int
+
+const (
+	confChangeTimeout configChangeType = iota
+	confChangeTimePerBlock
+	confChangeMaxBlockSize
+	confChangeMaxTxsPerBlock
+	confChangeEmptyBlockPolicy
+)
+
+// configChange is a single runtime configuration update, applied by the
+// consensus loop's handleConfigChange.
+type configChange struct {
+	typ  configChangeType
+	data interface{}
+}
+
+// handleConfigChange applies a single runtime configuration update received
+// on confCh. It runs on the consensus loop goroutine, same as the rest of
+// Start's select; timePerBlock/maxBlockSize/maxTxsPerBlock/emptyBlockPolicy
+// are still stored as atomics because ProposeTxs and ProposeTxsContext read
+// maxBlockSize and maxTxsPerBlock from the caller's goroutine.
+func (z *zbft) handleConfigChange(cch configChange) {
+	switch cch.typ {
+	case confChangeTimeout:
+		z.roundTimeout = cch.data.(time.Duration)
+
+	case confChangeTimePerBlock:
+		d := cch.data.(time.Duration)
+		atomic.StoreInt64(&z.timePerBlock, int64(d))
+		z.blockTimer.Reset(d)
+
+	case confChangeMaxBlockSize:
+		atomic.StoreInt64(&z.maxBlockSize, int64(cch.data.(int)))
+
+	case confChangeMaxTxsPerBlock:
+		atomic.StoreInt64(&z.maxTxsPerBlock, int64(cch.data.(int)))
+
+	case confChangeEmptyBlockPolicy:
+		atomic.StoreInt32(&z.emptyBlockPolicy, int32(cch.data.(EmptyBlockPolicy)))
+	}
+}
@@ -0,0 +1,35 @@
+package zbft
+
+import "github.com/hexablock/blockchain/bcpb"
+
+// execBlock carries a committed block to startExecing for FSM application.
+// height is threaded through explicitly, rather than read back off block,
+// so removeCommittedTxs doesn't need to know bcpb.BlockHeader's layout.
+type execBlock struct {
+	height uint64
+	block  *bcpb.BlockHeader
+	txs    []*bcpb.Tx
+	leader bool
+}
+
+// startExecing runs in its own goroutine for the life of the node, applying
+// every block consensus commits to the FSM in commit order. It is the
+// consumer side of z.exec, fed whenever this node observes a block reach
+// quorum commit.
+//
+// Once a block is applied, its txs are evicted from the mempool and the
+// WAL/dedup cache/roundLog are truncated up to its height via
+// removeCommittedTxs, which also advances z.height/z.round so proposeBlock
+// moves on to the next round instead of re-proposing the same one forever.
+func (z *zbft) startExecing() {
+	for eb := range z.exec {
+		z.metrics.SetQueueDepth("exec", len(z.exec))
+
+		if err := z.fsm.Execute(eb.txs, eb.block, eb.leader); err != nil {
+			z.log.Printf("[ERROR] failed executing committed block at height %d: %v", eb.height, err)
+			continue
+		}
+
+		z.removeCommittedTxs(eb.height, eb.txs)
+	}
+}
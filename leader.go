@@ -0,0 +1,137 @@
+package zbft
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/hexablock/blockchain/bcpb"
+	"github.com/hexablock/blockchain/keypair"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+// globalRound combines height and round into a single counter that
+// advances on every proposal attempt, never repeating across the life of
+// the chain. round alone resets to 0 on every committed block, so using it
+// in isolation as the beacon/hash input would have every block's round 0
+// request the same entry and therefore pick the same proposer forever;
+// height alone does not change across the rounds of one height. Keying off
+// globalRound instead gives every height/round pair its own entry. round is assumed to stay well under 2^32 (it only advances on
+// round-timeout), leaving height the high bits.
+func globalRound(height, round uint64) uint64 {
+	return height<<32 | (round & 0xffffffff)
+}
+
+// proposerIndex computes the index, into a validator set of size
+// numValidators, of the proposer for height/round using the configured
+// RandomnessBeacon as entropy on top of globalRound(height, round). With
+// the default NopBeacon (a nil entry) this degenerates to a deterministic
+// hash of globalRound alone, derived purely from on-chain state without
+// pinning every block to the same proposer.
+func (z *zbft) proposerIndex(height, round uint64, numValidators int) (int, []byte, error) {
+	if numValidators <= 0 {
+		return 0, nil, fmt.Errorf("zbft: no validators to select a proposer from")
+	}
+
+	gr := globalRound(height, round)
+
+	entry, err := z.beacon.Entry(gr)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return deriveProposerIndex(entry, gr, numValidators), entry, nil
+}
+
+// verifyProposer reports whether proposer was the validator selected for
+// height/round, re-deriving the expected proposer from the beacon entry
+// used by the proposal and rejecting it if the beacon entry does not
+// verify.
+func (z *zbft) verifyProposer(height, round uint64, validators []*keypair.PublicKey, beaconEntry []byte, prevEntry []byte, proposer *keypair.PublicKey) error {
+	if len(validators) == 0 {
+		return fmt.Errorf("zbft: no validators to select a proposer from")
+	}
+
+	gr := globalRound(height, round)
+
+	if err := z.beacon.Verify(gr, prevEntry, beaconEntry); err != nil {
+		return fmt.Errorf("zbft: invalid beacon entry: %w", err)
+	}
+
+	idx := deriveProposerIndex(beaconEntry, gr, len(validators))
+
+	if string(validators[idx].Bytes()) != string(proposer.Bytes()) {
+		return fmt.Errorf("zbft: proposal from %x, expected proposer %x", proposer.Bytes(), validators[idx].Bytes())
+	}
+
+	return nil
+}
+
+// validateProposal checks msg against verifyProposer when it is a PREPARE
+// message, using the beacon entry recorded for the previous round as
+// prevEntry. Every other message type is let through unchanged, since only
+// PREPARE carries a proposer to verify.
+func (z *zbft) validateProposal(msg zbftpb.Message) error {
+	if msg.Type != zbftpb.Message_PREPARE {
+		return nil
+	}
+
+	prevEntry := z.roundLog.PrevBeaconEntry(msg.Height, msg.Round)
+
+	return z.verifyProposer(msg.Height, msg.Round, z.validators, msg.BeaconEntry, prevEntry, msg.From)
+}
+
+// proposeBlock assembles a PREPARE message carrying txs for the current
+// height/round, using proposerIndex to determine whether this node is the
+// round's proposer. Non-proposers are a no-op: txs stay in the mempool for
+// whichever validator was actually selected. The beacon entry used to
+// derive the proposer is attached to the message as BeaconEntry, so
+// recipients can verify the selection via validateProposal.
+func (z *zbft) proposeBlock(txs []*bcpb.Tx) {
+	height := atomic.LoadUint64(&z.height)
+	round := atomic.LoadUint64(&z.round)
+
+	idx, entry, err := z.proposerIndex(height, round, len(z.validators))
+	if err != nil {
+		z.log.Printf("[ERROR] failed selecting proposer for height %d round %d: %v", height, round, err)
+		return
+	}
+
+	if string(z.validators[idx].Bytes()) != string(z.kp.PublicKey.Bytes()) {
+		return
+	}
+
+	msg := zbftpb.Message{
+		Type:        zbftpb.Message_PREPARE,
+		Height:      height,
+		Round:       round,
+		Txs:         txs,
+		From:        z.kp.PublicKey,
+		BeaconEntry: entry,
+	}
+
+	if err := z.wal.Append(msg); err != nil {
+		z.log.Printf("[ERROR] failed appending to WAL: %v", err)
+	}
+
+	z.roundLog.Record(msg)
+	z.broadcast(msg)
+}
+
+// deriveProposerIndex hashes entry together with round to pick an index
+// into a validator set of size numValidators. It is the pure computation
+// shared by proposerIndex and verifyProposer, split out so the hash/modulo
+// derivation can be tested without a beacon or validator set.
+func deriveProposerIndex(entry []byte, round uint64, numValidators int) int {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], round)
+
+	h := sha256.New()
+	h.Write(entry)
+	h.Write(buf[:])
+	sum := h.Sum(nil)
+
+	return int(binary.BigEndian.Uint64(sum[:8]) % uint64(numValidators))
+}
@@ -0,0 +1,44 @@
+package mempool
+
+import "container/list"
+
+// seenCache is a bounded, FIFO-evicted set of tx ids used to prevent
+// re-admission of transactions shortly after they are committed or evicted
+// from the pool.
+type seenCache struct {
+	cap     int
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+func newSeenCache(capacity int) *seenCache {
+	return &seenCache{
+		cap:     capacity,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Add inserts id into the cache, evicting the oldest entry if the cache is
+// at capacity.
+func (c *seenCache) Add(id string) {
+	if _, ok := c.entries[id]; ok {
+		return
+	}
+
+	if c.order.Len() >= c.cap {
+		oldest := c.order.Front()
+		if oldest != nil {
+			c.order.Remove(oldest)
+			delete(c.entries, oldest.Value.(string))
+		}
+	}
+
+	c.entries[id] = c.order.PushBack(id)
+}
+
+// Contains reports whether id is present in the cache.
+func (c *seenCache) Contains(id string) bool {
+	_, ok := c.entries[id]
+	return ok
+}
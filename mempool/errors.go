@@ -0,0 +1,12 @@
+package mempool
+
+import "errors"
+
+var (
+	// ErrFull is returned by Add when the pool is already at its configured
+	// MaxSize.
+	ErrFull = errors.New("mempool: full")
+	// ErrAlreadySeen is returned by Add when the transaction was recently
+	// committed or evicted and is present in the seen cache.
+	ErrAlreadySeen = errors.New("mempool: already seen")
+)
@@ -0,0 +1,208 @@
+// Package mempool implements a pluggable transaction pool that sits between
+// the transaction submitter and the consensus leader. Transactions are
+// admitted through a set of policies and held until the leader pulls a
+// verified batch to include in the next block.
+package mempool
+
+import (
+	"sync"
+
+	"github.com/hexablock/blockchain/bcpb"
+)
+
+// cacheMaxCapacity is the default number of recently seen tx ids retained to
+// prevent re-admission of a transaction shortly after it was committed or
+// evicted.
+const cacheMaxCapacity = 100
+
+// DefaultMaxTxSize and DefaultMaxPerSender are the default limits used by
+// MaxTxSizePolicy and PerSenderRateLimitPolicy when a caller wants sane
+// admission policies without hand-tuning them.
+const (
+	DefaultMaxTxSize    = 64 * 1024
+	DefaultMaxPerSender = 64
+)
+
+// DefaultPolicies returns the standard admission policies applied to a
+// mempool: a per-tx size cap, a per-sender rate limit and dependency
+// ordering against store. It deliberately does not include
+// MaxBlockSizePolicy: the leader's per-block size cap is enforced
+// separately at assembly time, and applying it at admission too would cap
+// the whole pool at one block's worth of bytes, defeating its purpose as a
+// backlog. Callers that want a coarse overall memory guard can add
+// MaxBlockSizePolicy themselves with MaxSize set to the pool's capacity.
+func DefaultPolicies(store TxStore) []Policy {
+	return []Policy{
+		&MaxTxSizePolicy{MaxSize: DefaultMaxTxSize},
+		&PerSenderRateLimitPolicy{MaxPerSender: DefaultMaxPerSender},
+		&DependencyOrderPolicy{Store: store},
+	}
+}
+
+// TxStore is the read-only view of previously committed outputs used to
+// order transactions that depend on each other within the same pool.
+type TxStore interface {
+	// Returns the output associated to the given input
+	GetTXO(txi *bcpb.TxInput) (*bcpb.TxOutput, error)
+}
+
+// Policy is a pluggable admission rule evaluated when a transaction is added
+// to the pool. A Policy returns a non-nil error to reject the transaction.
+type Policy interface {
+	// Admit is called for each candidate transaction prior to it being added
+	// to the pool. pending is every transaction already admitted to the
+	// pool, in admission order, excluding tx itself.
+	Admit(tx *bcpb.Tx, pending []*bcpb.Tx) error
+}
+
+// Config is the configuration used to initialize a Mempool.
+type Config struct {
+	// TxStore is kept on the Mempool but not consulted by Add; TxInput
+	// dependencies are resolved only by a DependencyOrderPolicy in Policies,
+	// against that policy's own Store. May be nil.
+	TxStore TxStore
+	// Policies are evaluated, in order, for every tx passed to Add. A
+	// transaction is admitted only if every policy returns a nil error.
+	Policies []Policy
+	// MaxSize caps the number of transactions held in the pool at once. A
+	// value <= 0 means unbounded.
+	MaxSize int
+	// SeenCacheSize bounds the recently-committed/evicted tx id cache used
+	// to reject re-admission. Defaults to cacheMaxCapacity when <= 0.
+	SeenCacheSize int
+}
+
+// Mempool holds admitted, not-yet-committed transactions and exposes them to
+// the leader for inclusion in the next proposed block.
+type Mempool struct {
+	mu sync.Mutex
+
+	store    TxStore
+	policies []Policy
+	maxSize  int
+
+	txs   map[string]*bcpb.Tx
+	order []string
+	seen  *seenCache
+}
+
+// New instantiates a Mempool from the given Config.
+func New(conf *Config) *Mempool {
+	if conf == nil {
+		conf = &Config{}
+	}
+
+	cacheSize := conf.SeenCacheSize
+	if cacheSize <= 0 {
+		cacheSize = cacheMaxCapacity
+	}
+
+	return &Mempool{
+		store:    conf.TxStore,
+		policies: conf.Policies,
+		maxSize:  conf.MaxSize,
+		txs:      make(map[string]*bcpb.Tx),
+		seen:     newSeenCache(cacheSize),
+	}
+}
+
+// Add runs tx through the configured admission policies and, if admitted,
+// inserts it into the pool. It returns the first policy error encountered,
+// ErrFull if the pool is at capacity, or ErrAlreadySeen if tx was recently
+// committed or evicted.
+func (m *Mempool) Add(tx *bcpb.Tx) error {
+	id := txID(tx)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.txs[id]; ok {
+		return nil
+	}
+
+	if m.seen.Contains(id) {
+		return ErrAlreadySeen
+	}
+
+	if m.maxSize > 0 && len(m.txs) >= m.maxSize {
+		return ErrFull
+	}
+
+	pending := m.pendingLocked()
+	for _, p := range m.policies {
+		if err := p.Admit(tx, pending); err != nil {
+			return err
+		}
+	}
+
+	m.txs[id] = tx
+	m.order = append(m.order, id)
+
+	return nil
+}
+
+// GetVerified returns up to maxCount admitted transactions for the leader to
+// include in the next block, in the order they were admitted (FIFO), so the
+// leader does not get a different arbitrary ordering on every call.
+// Transactions remain in the pool until RemoveCommitted is called with the
+// block that included them.
+func (m *Mempool) GetVerified(maxCount int) []*bcpb.Tx {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := m.pendingLocked()
+	if maxCount > 0 && len(out) > maxCount {
+		out = out[:maxCount]
+	}
+
+	return out
+}
+
+// RemoveCommitted evicts every transaction in block from the pool and records
+// their ids in the seen cache so they cannot be re-admitted.
+func (m *Mempool) RemoveCommitted(block []*bcpb.Tx) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tx := range block {
+		id := txID(tx)
+		delete(m.txs, id)
+		m.seen.Add(id)
+	}
+
+	m.order = pruneOrder(m.order, m.txs)
+}
+
+// Len returns the number of transactions currently admitted to the pool.
+func (m *Mempool) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.txs)
+}
+
+// pendingLocked returns the admitted transactions in insertion order.
+// Callers must hold m.mu.
+func (m *Mempool) pendingLocked() []*bcpb.Tx {
+	out := make([]*bcpb.Tx, 0, len(m.order))
+	for _, id := range m.order {
+		out = append(out, m.txs[id])
+	}
+	return out
+}
+
+// pruneOrder returns order with every id no longer present in txs removed,
+// preserving the relative order of the ids that remain.
+func pruneOrder(order []string, txs map[string]*bcpb.Tx) []string {
+	out := order[:0]
+	for _, id := range order {
+		if _, ok := txs[id]; ok {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// txID returns the string key used to index tx in txs/seen.
+func txID(tx *bcpb.Tx) string {
+	return string(tx.Hash)
+}
@@ -0,0 +1,126 @@
+package mempool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hexablock/blockchain/bcpb"
+)
+
+func newTx(hash string) *bcpb.Tx {
+	return &bcpb.Tx{Hash: []byte(hash)}
+}
+
+func TestMempoolAddGetVerified(t *testing.T) {
+	mp := New(&Config{})
+
+	if err := mp.Add(newTx("a")); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	if err := mp.Add(newTx("b")); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	if l := mp.Len(); l != 2 {
+		t.Fatalf("expected 2 txs, got %d", l)
+	}
+
+	txs := mp.GetVerified(1)
+	if len(txs) != 1 {
+		t.Fatalf("expected GetVerified(1) to return 1 tx, got %d", len(txs))
+	}
+}
+
+func TestMempoolAddDuplicateIsNoop(t *testing.T) {
+	mp := New(&Config{})
+	tx := newTx("a")
+
+	if err := mp.Add(tx); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+	if err := mp.Add(tx); err != nil {
+		t.Fatalf("re-Add of in-pool tx should be a no-op, got: %v", err)
+	}
+	if l := mp.Len(); l != 1 {
+		t.Fatalf("expected 1 tx after duplicate Add, got %d", l)
+	}
+}
+
+func TestMempoolRemoveCommittedRejectsReAdmission(t *testing.T) {
+	mp := New(&Config{})
+	tx := newTx("a")
+
+	if err := mp.Add(tx); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	mp.RemoveCommitted([]*bcpb.Tx{tx})
+
+	if l := mp.Len(); l != 0 {
+		t.Fatalf("expected empty pool after RemoveCommitted, got %d", l)
+	}
+
+	err := mp.Add(tx)
+	if !errors.Is(err, ErrAlreadySeen) {
+		t.Fatalf("expected ErrAlreadySeen, got: %v", err)
+	}
+}
+
+func TestMempoolMaxSize(t *testing.T) {
+	mp := New(&Config{MaxSize: 1})
+
+	if err := mp.Add(newTx("a")); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	err := mp.Add(newTx("b"))
+	if !errors.Is(err, ErrFull) {
+		t.Fatalf("expected ErrFull, got: %v", err)
+	}
+}
+
+func TestMempoolGetVerifiedIsFIFO(t *testing.T) {
+	mp := New(&Config{})
+
+	for _, h := range []string{"a", "b", "c"} {
+		if err := mp.Add(newTx(h)); err != nil {
+			t.Fatalf("Add(%s) returned error: %v", h, err)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		txs := mp.GetVerified(0)
+		if len(txs) != 3 {
+			t.Fatalf("expected 3 txs, got %d", len(txs))
+		}
+		for j, want := range []string{"a", "b", "c"} {
+			if string(txs[j].Hash) != want {
+				t.Fatalf("expected txs[%d] == %q, got %q", j, want, txs[j].Hash)
+			}
+		}
+	}
+
+	mp.RemoveCommitted([]*bcpb.Tx{newTx("b")})
+
+	txs := mp.GetVerified(0)
+	if len(txs) != 2 || string(txs[0].Hash) != "a" || string(txs[1].Hash) != "c" {
+		t.Fatalf("expected [a c] after removing b, got %v", txs)
+	}
+}
+
+type rejectPolicy struct{}
+
+func (rejectPolicy) Admit(tx *bcpb.Tx, pending []*bcpb.Tx) error {
+	return errors.New("rejected by policy")
+}
+
+func TestMempoolPolicyRejection(t *testing.T) {
+	mp := New(&Config{Policies: []Policy{rejectPolicy{}}})
+
+	if err := mp.Add(newTx("a")); err == nil {
+		t.Fatal("expected policy to reject tx")
+	}
+	if l := mp.Len(); l != 0 {
+		t.Fatalf("expected 0 txs after rejection, got %d", l)
+	}
+}
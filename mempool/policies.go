@@ -0,0 +1,111 @@
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/hexablock/blockchain/bcpb"
+)
+
+// MaxBlockSizePolicy rejects a transaction once the cumulative serialized
+// size of the pending pool, including the candidate, would exceed MaxSize.
+// MaxSize should be set to the pool's overall capacity, not the leader's
+// per-block limit: the per-block limit is already enforced at assembly
+// time (see boundTxsBySize in the zbft package), and admitting only one
+// block's worth of bytes total would make Add start permanently rejecting
+// new transactions as soon as the pool fills up, even though older
+// transactions are about to be evicted into upcoming blocks.
+type MaxBlockSizePolicy struct {
+	MaxSize int
+}
+
+// Admit implements Policy.
+func (p *MaxBlockSizePolicy) Admit(tx *bcpb.Tx, pending []*bcpb.Tx) error {
+	total := tx.Size()
+	for _, t := range pending {
+		total += t.Size()
+	}
+
+	if total > p.MaxSize {
+		return fmt.Errorf("mempool: block size limit of %d bytes exceeded", p.MaxSize)
+	}
+
+	return nil
+}
+
+// MaxTxSizePolicy rejects any single transaction larger than MaxSize.
+type MaxTxSizePolicy struct {
+	MaxSize int
+}
+
+// Admit implements Policy.
+func (p *MaxTxSizePolicy) Admit(tx *bcpb.Tx, pending []*bcpb.Tx) error {
+	if s := tx.Size(); s > p.MaxSize {
+		return fmt.Errorf("mempool: tx size %d exceeds max tx size %d", s, p.MaxSize)
+	}
+
+	return nil
+}
+
+// PerSenderRateLimitPolicy rejects a transaction once its sender already
+// has MaxPerSender transactions pending.
+type PerSenderRateLimitPolicy struct {
+	MaxPerSender int
+}
+
+// Admit implements Policy.
+func (p *PerSenderRateLimitPolicy) Admit(tx *bcpb.Tx, pending []*bcpb.Tx) error {
+	sender := string(tx.Sender)
+
+	n := 0
+	for _, t := range pending {
+		if string(t.Sender) == sender {
+			n++
+		}
+	}
+
+	if n >= p.MaxPerSender {
+		return fmt.Errorf("mempool: sender %x exceeds %d pending txs", tx.Sender, p.MaxPerSender)
+	}
+
+	return nil
+}
+
+// DependencyOrderPolicy rejects a transaction whose inputs reference
+// outputs that are neither already confirmed in store nor produced by a tx
+// already pending in the same pool, so dependent transactions are admitted
+// only once the transaction they depend on is.
+type DependencyOrderPolicy struct {
+	Store TxStore
+}
+
+// Admit implements Policy.
+func (p *DependencyOrderPolicy) Admit(tx *bcpb.Tx, pending []*bcpb.Tx) error {
+	for _, txi := range tx.Inputs {
+		if p.resolvedByStore(txi) || p.resolvedByPending(txi, pending) {
+			continue
+		}
+
+		return fmt.Errorf("mempool: tx references unresolved input from tx %x", txi.TxHash)
+	}
+
+	return nil
+}
+
+func (p *DependencyOrderPolicy) resolvedByStore(txi *bcpb.TxInput) bool {
+	if p.Store == nil {
+		return false
+	}
+
+	_, err := p.Store.GetTXO(txi)
+	return err == nil
+}
+
+func (p *DependencyOrderPolicy) resolvedByPending(txi *bcpb.TxInput, pending []*bcpb.Tx) bool {
+	for _, t := range pending {
+		if txID(t) == string(txi.TxHash) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,90 @@
+package mempool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hexablock/blockchain/bcpb"
+)
+
+func TestMaxBlockSizePolicy(t *testing.T) {
+	mp := New(&Config{Policies: []Policy{&MaxBlockSizePolicy{MaxSize: 10}}})
+
+	if err := mp.Add(&bcpb.Tx{Hash: []byte("a")}); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+}
+
+func TestMaxTxSizePolicy(t *testing.T) {
+	mp := New(&Config{Policies: []Policy{&MaxTxSizePolicy{MaxSize: 0}}})
+
+	err := mp.Add(&bcpb.Tx{Hash: []byte("a")})
+	if err == nil {
+		t.Fatal("expected tx exceeding MaxSize to be rejected")
+	}
+}
+
+func TestPerSenderRateLimitPolicy(t *testing.T) {
+	mp := New(&Config{Policies: []Policy{&PerSenderRateLimitPolicy{MaxPerSender: 1}}})
+
+	if err := mp.Add(&bcpb.Tx{Hash: []byte("a"), Sender: []byte("alice")}); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	err := mp.Add(&bcpb.Tx{Hash: []byte("b"), Sender: []byte("alice")})
+	if err == nil {
+		t.Fatal("expected second tx from same sender to be rejected")
+	}
+
+	if err = mp.Add(&bcpb.Tx{Hash: []byte("c"), Sender: []byte("bob")}); err != nil {
+		t.Fatalf("expected tx from a different sender to be admitted, got: %v", err)
+	}
+}
+
+type fakeTxStore struct {
+	known map[string]bool
+}
+
+func (s fakeTxStore) GetTXO(txi *bcpb.TxInput) (*bcpb.TxOutput, error) {
+	if s.known[string(txi.TxHash)] {
+		return &bcpb.TxOutput{}, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func TestDependencyOrderPolicy(t *testing.T) {
+	store := fakeTxStore{known: map[string]bool{"confirmed": true}}
+	mp := New(&Config{
+		TxStore:  store,
+		Policies: []Policy{&DependencyOrderPolicy{Store: store}},
+	})
+
+	confirmedInput := &bcpb.Tx{
+		Hash:   []byte("a"),
+		Inputs: []*bcpb.TxInput{{TxHash: []byte("confirmed")}},
+	}
+	if err := mp.Add(confirmedInput); err != nil {
+		t.Fatalf("expected tx with confirmed input to be admitted, got: %v", err)
+	}
+
+	unresolved := &bcpb.Tx{
+		Hash:   []byte("b"),
+		Inputs: []*bcpb.TxInput{{TxHash: []byte("missing")}},
+	}
+	if err := mp.Add(unresolved); err == nil {
+		t.Fatal("expected tx with unresolved input to be rejected")
+	}
+
+	pendingParent := &bcpb.Tx{Hash: []byte("parent")}
+	if err := mp.Add(pendingParent); err != nil {
+		t.Fatalf("Add returned error: %v", err)
+	}
+
+	child := &bcpb.Tx{
+		Hash:   []byte("child"),
+		Inputs: []*bcpb.TxInput{{TxHash: []byte("parent")}},
+	}
+	if err := mp.Add(child); err != nil {
+		t.Fatalf("expected tx depending on a pending tx to be admitted, got: %v", err)
+	}
+}
@@ -0,0 +1,20 @@
+package zbft
+
+// Metrics is an optional hook used to export Prometheus-style counters and
+// gauges for the consensus engine's internal queues. A nil Metrics on
+// Config disables collection.
+type Metrics interface {
+	// IncMsgsDropped increments the msgs_dropped_total counter for the given
+	// reason (e.g. "busy", "not_ready", "rate_limited").
+	IncMsgsDropped(reason string)
+	// SetQueueDepth reports the current depth of the named queue (e.g.
+	// "msg_in", "msg_bcast", "tx_collect", "exec").
+	SetQueueDepth(queue string, depth int)
+}
+
+// nopMetrics is the default Metrics implementation used when Config.Metrics
+// is nil. It discards every observation.
+type nopMetrics struct{}
+
+func (nopMetrics) IncMsgsDropped(reason string)          {}
+func (nopMetrics) SetQueueDepth(queue string, depth int) {}
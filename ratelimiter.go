@@ -0,0 +1,68 @@
+package zbft
+
+import (
+	"sync"
+	"time"
+
+	"github.com/hexablock/blockchain/keypair"
+)
+
+// defaultPeerRateLimit is the default number of messages a single peer may
+// submit per defaultPeerRateWindow before StepContext starts rejecting them.
+const (
+	defaultPeerRateLimit  = 32
+	defaultPeerRateWindow = time.Second
+)
+
+// peerRateLimiter bounds how many messages a single peer can push into the
+// inbox per window, so one misbehaving or compromised node cannot fill the
+// queue and starve the rest of the network.
+type peerRateLimiter struct {
+	mu     sync.Mutex
+	limit  int
+	window time.Duration
+	counts map[string]*peerCount
+}
+
+type peerCount struct {
+	n         int
+	windowEnd time.Time
+}
+
+func newPeerRateLimiter(limit int, window time.Duration) *peerRateLimiter {
+	if limit <= 0 {
+		limit = defaultPeerRateLimit
+	}
+	if window <= 0 {
+		window = defaultPeerRateWindow
+	}
+
+	return &peerRateLimiter{
+		limit:  limit,
+		window: window,
+		counts: make(map[string]*peerCount),
+	}
+}
+
+// Allow reports whether a message from peer may be admitted, incrementing
+// its count for the current window as a side effect.
+func (r *peerRateLimiter) Allow(peer *keypair.PublicKey, now time.Time) bool {
+	return r.allowKey(string(peer.Bytes()), now)
+}
+
+// allowKey is the window/count bookkeeping behind Allow, split out so it can
+// be tested without a *keypair.PublicKey.
+func (r *peerRateLimiter) allowKey(key string, now time.Time) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pc, ok := r.counts[key]
+	if !ok || now.After(pc.windowEnd) {
+		pc = &peerCount{windowEnd: now.Add(r.window)}
+		r.counts[key] = pc
+	}
+
+	pc.n++
+
+	return pc.n <= r.limit
+}
@@ -0,0 +1,61 @@
+package zbft
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPeerRateLimiterAllowsUpToLimit(t *testing.T) {
+	r := newPeerRateLimiter(3, time.Second)
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 3; i++ {
+		if !r.allowKey("peer-a", now) {
+			t.Fatalf("expected message %d to be allowed within the limit", i+1)
+		}
+	}
+
+	if r.allowKey("peer-a", now) {
+		t.Fatal("expected message exceeding the limit to be rejected")
+	}
+}
+
+func TestPeerRateLimiterResetsOnNewWindow(t *testing.T) {
+	r := newPeerRateLimiter(1, time.Second)
+	now := time.Unix(0, 0)
+
+	if !r.allowKey("peer-a", now) {
+		t.Fatal("expected first message to be allowed")
+	}
+	if r.allowKey("peer-a", now) {
+		t.Fatal("expected second message within the same window to be rejected")
+	}
+
+	next := now.Add(time.Second + time.Millisecond)
+	if !r.allowKey("peer-a", next) {
+		t.Fatal("expected a message in the next window to be allowed")
+	}
+}
+
+func TestPeerRateLimiterTracksPeersIndependently(t *testing.T) {
+	r := newPeerRateLimiter(1, time.Second)
+	now := time.Unix(0, 0)
+
+	if !r.allowKey("peer-a", now) {
+		t.Fatal("expected peer-a's first message to be allowed")
+	}
+	if !r.allowKey("peer-b", now) {
+		t.Fatal("expected peer-b's first message to be allowed regardless of peer-a's count")
+	}
+}
+
+func TestNewPeerRateLimiterDefaults(t *testing.T) {
+	r := newPeerRateLimiter(0, 0)
+
+	if r.limit != defaultPeerRateLimit {
+		t.Fatalf("expected default limit %d, got %d", defaultPeerRateLimit, r.limit)
+	}
+	if r.window != defaultPeerRateWindow {
+		t.Fatalf("expected default window %v, got %v", defaultPeerRateWindow, r.window)
+	}
+}
@@ -0,0 +1,107 @@
+package zbft
+
+import (
+	"sync"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+// roundKey identifies a consensus round by height and round number.
+type roundKey struct {
+	height uint64
+	round  uint64
+}
+
+// roundLog records the PREPARE/COMMIT messages this node has seen for each
+// round, so it can answer a peer's RecoveryRequest without depending on the
+// full internal round state.
+type roundLog struct {
+	mu  sync.Mutex
+	log map[roundKey][]zbftpb.Message
+}
+
+func newRoundLog() *roundLog {
+	return &roundLog{log: make(map[roundKey][]zbftpb.Message)}
+}
+
+// Record stores msg if it is a PREPARE or COMMIT message; any other type is
+// ignored.
+func (r *roundLog) Record(msg zbftpb.Message) {
+	if msg.Type != zbftpb.Message_PREPARE && msg.Type != zbftpb.Message_COMMIT {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	k := roundKey{height: msg.Height, round: msg.Round}
+	r.log[k] = append(r.log[k], msg)
+}
+
+// Collect returns the prepare and commit messages recorded for the given
+// height/round.
+func (r *roundLog) Collect(height, round uint64) (preparations, commits []zbftpb.Message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, msg := range r.log[roundKey{height: height, round: round}] {
+		if msg.Type == zbftpb.Message_PREPARE {
+			preparations = append(preparations, msg)
+		} else {
+			commits = append(commits, msg)
+		}
+	}
+
+	return
+}
+
+// HasPrepare reports whether this node has recorded a PREPARE for the given
+// height/round. It is used to detect a COMMIT arriving for a round this
+// node never saw a proposal for, so it can ask the sender for a recovery.
+func (r *roundLog) HasPrepare(height, round uint64) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, msg := range r.log[roundKey{height: height, round: round}] {
+		if msg.Type == zbftpb.Message_PREPARE {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PrevBeaconEntry returns the BeaconEntry carried by the PREPARE message
+// this node recorded for round-1 at height, or nil if it has not recorded
+// one (the first round of a height, or a node that has not yet seen that
+// round's proposal). It is used by verifyProposer to check that a
+// proposal's beacon entry is a valid successor to the previous round's.
+func (r *roundLog) PrevBeaconEntry(height, round uint64) []byte {
+	if round == 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, msg := range r.log[roundKey{height: height, round: round - 1}] {
+		if msg.Type == zbftpb.Message_PREPARE {
+			return msg.BeaconEntry
+		}
+	}
+
+	return nil
+}
+
+// ForgetBefore discards entries for heights less than height, called
+// alongside WAL truncation on block commit.
+func (r *roundLog) ForgetBefore(height uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for k := range r.log {
+		if k.height < height {
+			delete(r.log, k)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package transport
+
+import (
+	"sync"
+
+	"github.com/hexablock/blockchain/keypair"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+// ChanTransport is the default Transport, backed by in-process channels. It
+// preserves the original behavior of draining ZBFT.BroadcastMessages() for
+// Broadcast. Send unicasts to a peer registered via Connect; a peer that
+// hasn't been registered has no addressable inbound channel to unicast to,
+// so Send falls back to Broadcast for it.
+type ChanTransport struct {
+	out chan zbftpb.Message
+	in  chan zbftpb.Message
+
+	mu    sync.Mutex
+	peers map[string]chan<- zbftpb.Message
+}
+
+// NewChanTransport creates a ChanTransport with the given outbound and
+// inbound channel capacity.
+func NewChanTransport(capacity int) *ChanTransport {
+	return &ChanTransport{
+		out:   make(chan zbftpb.Message, capacity),
+		in:    make(chan zbftpb.Message, capacity),
+		peers: make(map[string]chan<- zbftpb.Message),
+	}
+}
+
+// Connect registers peer's inbound channel under pubKey, so a later Send
+// addressed to pubKey is delivered directly to peer instead of broadcast to
+// every node wired to this transport. Used to wire ChanTransports for
+// other nodes into this one, e.g. for an in-process test cluster.
+func (t *ChanTransport) Connect(pubKey *keypair.PublicKey, peer *ChanTransport) {
+	t.connectKey(string(pubKey.Bytes()), peer.in)
+}
+
+// connectKey is the registration behind Connect, split out so it can be
+// tested without a *keypair.PublicKey.
+func (t *ChanTransport) connectKey(key string, in chan<- zbftpb.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.peers[key] = in
+}
+
+// Broadcast queues msg on this transport's outbound channel, returned by
+// Out, for the caller to bridge to every other node.
+func (t *ChanTransport) Broadcast(msg zbftpb.Message) error {
+	t.out <- msg
+	return nil
+}
+
+// Send unicasts msg to peer's inbound channel if peer has been registered
+// via Connect. Otherwise there is no addressable channel for peer, so Send
+// falls back to Broadcast rather than silently dropping msg.
+func (t *ChanTransport) Send(peer *keypair.PublicKey, msg zbftpb.Message) error {
+	if peer != nil {
+		if ok := t.sendKey(string(peer.Bytes()), msg); ok {
+			return nil
+		}
+	}
+
+	return t.Broadcast(msg)
+}
+
+// sendKey is the lookup-and-deliver behind Send, split out so it can be
+// tested without a *keypair.PublicKey. It reports whether key had a
+// registered channel to deliver msg to.
+func (t *ChanTransport) sendKey(key string, msg zbftpb.Message) bool {
+	t.mu.Lock()
+	in, ok := t.peers[key]
+	t.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+
+	in <- msg
+	return true
+}
+
+// Receive returns the channel of messages destined for this node.
+func (t *ChanTransport) Receive() <-chan zbftpb.Message {
+	return t.in
+}
+
+// Out exposes the outbound channel so callers can bridge it to the network,
+// mirroring the previous ZBFT.BroadcastMessages() channel.
+func (t *ChanTransport) Out() <-chan zbftpb.Message {
+	return t.out
+}
+
+// In returns the inbound channel so a caller can feed received network
+// messages into the transport.
+func (t *ChanTransport) In() chan<- zbftpb.Message {
+	return t.in
+}
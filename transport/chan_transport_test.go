@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"testing"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+func TestChanTransportBroadcastReceive(t *testing.T) {
+	tr := NewChanTransport(1)
+
+	go func() {
+		tr.In() <- zbftpb.Message{Type: zbftpb.Message_PREPARE}
+	}()
+
+	msg := <-tr.Receive()
+	if msg.Type != zbftpb.Message_PREPARE {
+		t.Fatalf("expected Message_PREPARE, got %v", msg.Type)
+	}
+
+	if err := tr.Broadcast(zbftpb.Message{Type: zbftpb.Message_COMMIT}); err != nil {
+		t.Fatalf("Broadcast returned error: %v", err)
+	}
+
+	out := <-tr.Out()
+	if out.Type != zbftpb.Message_COMMIT {
+		t.Fatalf("expected Message_COMMIT, got %v", out.Type)
+	}
+}
+
+func TestChanTransportSendUnicastsToConnectedPeer(t *testing.T) {
+	a := NewChanTransport(1)
+	b := NewChanTransport(1)
+
+	a.connectKey("peer-b", b.in)
+
+	if !a.sendKey("peer-b", zbftpb.Message{Type: zbftpb.Message_RECOVERY_MESSAGE}) {
+		t.Fatal("expected sendKey to find peer-b's registered channel")
+	}
+
+	select {
+	case msg := <-b.Receive():
+		if msg.Type != zbftpb.Message_RECOVERY_MESSAGE {
+			t.Fatalf("expected Message_RECOVERY_MESSAGE, got %v", msg.Type)
+		}
+	default:
+		t.Fatal("expected msg to be delivered directly to peer-b, not broadcast")
+	}
+
+	select {
+	case <-a.Out():
+		t.Fatal("expected a unicast to a connected peer not to also hit Broadcast")
+	default:
+	}
+}
+
+func TestChanTransportSendFallsBackToBroadcastForUnknownPeer(t *testing.T) {
+	tr := NewChanTransport(1)
+
+	if ok := tr.sendKey("unknown-peer", zbftpb.Message{Type: zbftpb.Message_COMMIT}); ok {
+		t.Fatal("expected sendKey to report no registered channel for an unknown peer")
+	}
+
+	if err := tr.Send(nil, zbftpb.Message{Type: zbftpb.Message_COMMIT}); err != nil {
+		t.Fatalf("Send returned error: %v", err)
+	}
+
+	out := <-tr.Out()
+	if out.Type != zbftpb.Message_COMMIT {
+		t.Fatalf("expected the fallback Broadcast to queue Message_COMMIT, got %v", out.Type)
+	}
+}
@@ -0,0 +1,23 @@
+// Package transport defines the pluggable network layer used by zbft to
+// move consensus messages between peers. It exists so a user can supply a
+// libp2p, gRPC or raw TCP implementation instead of draining
+// ZBFT.BroadcastMessages() themselves.
+package transport
+
+import (
+	"github.com/hexablock/blockchain/keypair"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+// Transport moves consensus messages between this node and its peers.
+type Transport interface {
+	// Broadcast sends msg to every known peer.
+	Broadcast(msg zbftpb.Message) error
+	// Send unicasts msg to a single peer, identified by its public key.
+	// Used for responses that should not be broadcast, such as
+	// RecoveryMessage replies.
+	Send(peer *keypair.PublicKey, msg zbftpb.Message) error
+	// Receive returns the channel of messages arriving from peers.
+	Receive() <-chan zbftpb.Message
+}
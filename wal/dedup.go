@@ -0,0 +1,74 @@
+package wal
+
+import (
+	"sync"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+// dedupKey identifies a message for the purpose of rejecting replays after
+// recovery, mirroring dbft's (height, round, from, type) dedup cache.
+type dedupKey struct {
+	height uint64
+	round  uint64
+	from   string
+	typ    zbftpb.Message_Type
+}
+
+func keyFor(msg zbftpb.Message) dedupKey {
+	var from string
+	if msg.From != nil {
+		from = string(msg.From.Bytes())
+	}
+
+	return dedupKey{
+		height: msg.Height,
+		round:  msg.Round,
+		from:   from,
+		typ:    msg.Type,
+	}
+}
+
+// DedupCache rejects messages already seen for the same
+// (height, round, from, type) tuple, so replaying the WAL after a restart
+// does not reprocess a message the node already acted on.
+type DedupCache struct {
+	mu   sync.Mutex
+	seen map[dedupKey]struct{}
+}
+
+// NewDedupCache creates an empty DedupCache.
+func NewDedupCache() *DedupCache {
+	return &DedupCache{seen: make(map[dedupKey]struct{})}
+}
+
+// Seen reports whether msg was already recorded, recording it as a side
+// effect if not. Safe for concurrent use: Step and StepContext may be
+// called concurrently from multiple peer-handling goroutines.
+func (d *DedupCache) Seen(msg zbftpb.Message) bool {
+	k := keyFor(msg)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.seen[k]; ok {
+		return true
+	}
+
+	d.seen[k] = struct{}{}
+
+	return false
+}
+
+// ForgetBefore discards dedup entries for heights less than height, called
+// alongside WAL.TruncateBefore on block commit.
+func (d *DedupCache) ForgetBefore(height uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for k := range d.seen {
+		if k.height < height {
+			delete(d.seen, k)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+// FileWAL is a WAL backed by an append-only file of length-prefixed,
+// gob-encoded messages.
+type FileWAL struct {
+	mu   sync.Mutex
+	path string
+	f    *os.File
+	w    *bufio.Writer
+}
+
+// NewFileWAL opens (creating if necessary) the WAL file at path.
+func NewFileWAL(path string) (*FileWAL, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileWAL{path: path, f: f, w: bufio.NewWriter(f)}, nil
+}
+
+// Append persists msg to the file, flushing and syncing it before returning
+// so it is durable against a subsequent crash.
+func (w *FileWAL) Append(msg zbftpb.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := writeRecord(w.w, msg); err != nil {
+		return err
+	}
+
+	return w.f.Sync()
+}
+
+func writeRecord(w *bufio.Writer, msg zbftpb.Message) error {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&msg); err != nil {
+		return err
+	}
+
+	var lenPrefix [4]byte
+	binary.BigEndian.PutUint32(lenPrefix[:], uint32(buf.Len()))
+
+	if _, err := w.Write(lenPrefix[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+
+	return w.Flush()
+}
+
+func readRecords(r *bufio.Reader) ([]zbftpb.Message, error) {
+	var out []zbftpb.Message
+
+	for {
+		var lenPrefix [4]byte
+		if _, err := io.ReadFull(r, lenPrefix[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, err
+		}
+
+		buf := make([]byte, binary.BigEndian.Uint32(lenPrefix[:]))
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+
+		var msg zbftpb.Message
+		if err := gob.NewDecoder(bytes.NewReader(buf)).Decode(&msg); err != nil {
+			return nil, err
+		}
+
+		out = append(out, msg)
+	}
+
+	return out, nil
+}
+
+// Replay reads every message persisted to the file from the start.
+func (w *FileWAL) Replay() ([]zbftpb.Message, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.w.Flush(); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(w.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readRecords(bufio.NewReader(f))
+}
+
+// TruncateBefore rewrites the WAL file keeping only messages with a height
+// greater than or equal to height, called once a block at that height
+// commits.
+func (w *FileWAL) TruncateBefore(height uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+
+	f, err := os.Open(w.path)
+	if err != nil {
+		return err
+	}
+	kept, err := readRecords(bufio.NewReader(f))
+	f.Close()
+	if err != nil {
+		return err
+	}
+
+	tmpPath := w.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(tmp)
+	for _, msg := range kept {
+		if msg.Height < height {
+			continue
+		}
+		if err = writeRecord(bw, msg); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = w.f.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, w.path); err != nil {
+		return err
+	}
+
+	f, err = os.OpenFile(w.path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	w.f = f
+	w.w = bufio.NewWriter(f)
+
+	return nil
+}
+
+// Close flushes and closes the underlying file.
+func (w *FileWAL) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+
+	return w.f.Close()
+}
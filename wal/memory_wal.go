@@ -0,0 +1,58 @@
+package wal
+
+import (
+	"sync"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+// MemWAL is an in-memory WAL used in tests and other situations where
+// crash recovery is not required.
+type MemWAL struct {
+	mu  sync.Mutex
+	log []zbftpb.Message
+}
+
+// NewMemWAL creates an empty MemWAL.
+func NewMemWAL() *MemWAL {
+	return &MemWAL{}
+}
+
+// Append records msg.
+func (w *MemWAL) Append(msg zbftpb.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.log = append(w.log, msg)
+	return nil
+}
+
+// Replay returns every recorded message not yet discarded by TruncateBefore.
+func (w *MemWAL) Replay() ([]zbftpb.Message, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	out := make([]zbftpb.Message, len(w.log))
+	copy(out, w.log)
+
+	return out, nil
+}
+
+// TruncateBefore discards every recorded message for a height less than
+// height.
+func (w *MemWAL) TruncateBefore(height uint64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	kept := w.log[:0]
+	for _, msg := range w.log {
+		if msg.Height >= height {
+			kept = append(kept, msg)
+		}
+	}
+	w.log = kept
+
+	return nil
+}
+
+// Close is a no-op for MemWAL.
+func (w *MemWAL) Close() error { return nil }
@@ -0,0 +1,22 @@
+// Package wal implements a write-ahead log for zbft consensus messages, so
+// a node can recover in-flight prepare/commit state after a crash instead
+// of forcing a full round timeout across the network.
+package wal
+
+import "github.com/hexablock/zbft/zbftpb"
+
+// WAL persists every consensus message a node sends or accepts for the
+// current height/round, so Start can replay it on restart and resume
+// mid-round.
+type WAL interface {
+	// Append persists msg. It must return only after msg is durable.
+	Append(msg zbftpb.Message) error
+	// Replay returns every persisted message not yet discarded by
+	// TruncateBefore, in the order they were appended.
+	Replay() ([]zbftpb.Message, error)
+	// TruncateBefore discards every persisted message for a height less
+	// than height, called once a block at that height commits.
+	TruncateBefore(height uint64) error
+	// Close releases any resources held by the WAL.
+	Close() error
+}
@@ -0,0 +1,80 @@
+package wal
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hexablock/zbft/zbftpb"
+)
+
+func testWALImpl(t *testing.T, w WAL) {
+	t.Helper()
+
+	msgs := []zbftpb.Message{
+		{Type: zbftpb.Message_PREPARE, Height: 1, Round: 0},
+		{Type: zbftpb.Message_COMMIT, Height: 1, Round: 0},
+		{Type: zbftpb.Message_PREPARE, Height: 2, Round: 0},
+	}
+
+	for _, m := range msgs {
+		if err := w.Append(m); err != nil {
+			t.Fatalf("Append returned error: %v", err)
+		}
+	}
+
+	replayed, err := w.Replay()
+	if err != nil {
+		t.Fatalf("Replay returned error: %v", err)
+	}
+	if len(replayed) != len(msgs) {
+		t.Fatalf("expected %d replayed messages, got %d", len(msgs), len(replayed))
+	}
+
+	if err = w.TruncateBefore(2); err != nil {
+		t.Fatalf("TruncateBefore returned error: %v", err)
+	}
+
+	replayed, err = w.Replay()
+	if err != nil {
+		t.Fatalf("Replay after truncate returned error: %v", err)
+	}
+	if len(replayed) != 1 {
+		t.Fatalf("expected 1 message after truncating before height 2, got %d", len(replayed))
+	}
+	if replayed[0].Height != 2 {
+		t.Fatalf("expected remaining message at height 2, got %d", replayed[0].Height)
+	}
+}
+
+func TestMemWAL(t *testing.T) {
+	testWALImpl(t, NewMemWAL())
+}
+
+func TestFileWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "consensus.wal")
+
+	w, err := NewFileWAL(path)
+	if err != nil {
+		t.Fatalf("NewFileWAL returned error: %v", err)
+	}
+	defer w.Close()
+
+	testWALImpl(t, w)
+}
+
+func TestDedupCache(t *testing.T) {
+	d := NewDedupCache()
+	msg := zbftpb.Message{Type: zbftpb.Message_PREPARE, Height: 1, Round: 0}
+
+	if d.Seen(msg) {
+		t.Fatal("expected first observation to be unseen")
+	}
+	if !d.Seen(msg) {
+		t.Fatal("expected second observation to be seen")
+	}
+
+	d.ForgetBefore(2)
+	if d.Seen(msg) {
+		t.Fatal("expected entry below height 2 to be forgotten")
+	}
+}
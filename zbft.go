@@ -1,6 +1,10 @@
 package zbft
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/hexablock/blockchain"
@@ -8,9 +12,31 @@ import (
 	"github.com/hexablock/blockchain/keypair"
 	"github.com/hexablock/log"
 
+	"github.com/hexablock/zbft/beacon"
+	"github.com/hexablock/zbft/mempool"
+	"github.com/hexablock/zbft/transport"
+	"github.com/hexablock/zbft/wal"
 	"github.com/hexablock/zbft/zbftpb"
 )
 
+const defaultQueueSize = 16
+
+var (
+	// ErrBusy is returned by StepContext when the inbox is full.
+	ErrBusy = errors.New("zbft: busy")
+	// ErrNotReady is returned by StepContext when called before Start has
+	// replayed the WAL and begun its consensus loop, or while Config.ChainSync
+	// reports the node has not caught up to the chain tip.
+	ErrNotReady = errors.New("zbft: not ready")
+	// ErrRateLimited is returned by StepContext when the sending peer has
+	// exceeded its configured message rate.
+	ErrRateLimited = errors.New("zbft: rate limited")
+	// ErrInvalidProposer is returned by StepContext when a PREPARE
+	// message's beacon entry does not verify, or was not sent by the
+	// validator proposerIndex selected for its round.
+	ErrInvalidProposer = errors.New("zbft: invalid proposer")
+)
+
 // TxStore implements a transaction store for read-only purposes
 type TxStore interface {
 	// Returns the output associated to the given input
@@ -38,6 +64,71 @@ type Config struct {
 	// Finite state machine for the blockchain
 	FSM FSM
 
+	// Mempool admits and prioritizes transactions for the leader to include
+	// in a block. When nil, a default Mempool is built with
+	// mempool.DefaultPolicies (tx size, per-sender rate limit and
+	// dependency ordering against Blockchain); the per-block size cap is
+	// enforced separately, at assembly time, from MaxBlockSize.
+	Mempool *mempool.Mempool
+
+	// Transport moves consensus messages between this node and its peers.
+	// A channel-backed transport equivalent to the previous
+	// BroadcastMessages()/Step() handoff is used when nil.
+	Transport transport.Transport
+
+	// QueueSize bounds the msgIn, msgBcast, txCollect and exec channels.
+	// Defaults to defaultQueueSize when <= 0.
+	QueueSize int
+
+	// PeerRateLimit bounds how many messages StepContext accepts from a
+	// single peer per PeerRateWindow. Defaults to defaultPeerRateLimit when
+	// <= 0.
+	PeerRateLimit int
+	// PeerRateWindow is the window PeerRateLimit is measured over. Defaults
+	// to defaultPeerRateWindow when <= 0.
+	PeerRateWindow time.Duration
+
+	// Metrics, when set, is notified of dropped messages and queue depth.
+	Metrics Metrics
+
+	// Beacon supplies per-round entropy used, on top of the validator set,
+	// to compute the proposer for a round. Defaults to beacon.NopBeacon{},
+	// which reproduces the previous purely on-chain-derived selection.
+	Beacon beacon.RandomnessBeacon
+
+	// Validators is the ordered set of validator public keys Beacon's
+	// entropy is combined with to select each round's proposer. Proposals
+	// whose sender does not match the selected validator are rejected by
+	// StepContext/Step.
+	Validators []*keypair.PublicKey
+
+	// WAL persists every message this node sends or accepts for the
+	// current height/round, so Start can replay it and resume mid-round
+	// after a crash. Defaults to an in-memory WAL, which provides no
+	// crash recovery, when nil.
+	WAL wal.WAL
+
+	// TimePerBlock governs how often the leader proposes a new block even
+	// without pending transactions. Defaults to defaultTimePerBlock.
+	TimePerBlock time.Duration
+	// MaxBlockSize caps the serialized size, in bytes, of a block the
+	// leader assembles from the mempool. Defaults to defaultMaxBlockSize.
+	MaxBlockSize int
+	// MaxTxsPerBlock caps the number of transactions the leader pulls from
+	// the mempool per block. Defaults to defaultMaxTxsPerBlock.
+	MaxTxsPerBlock int
+	// EmptyBlockPolicy controls whether empty blocks are produced to keep
+	// the chain live. Defaults to EmptyBlockOnDemand.
+	EmptyBlockPolicy EmptyBlockPolicy
+
+	// ChainSync reports whether the local node has caught up to the
+	// network's chain tip. Ready() consults it in addition to Start having
+	// begun its consensus loop. Defaults to a no-op that always reports
+	// synced, matching previous behavior, since this tree has no chain-tip
+	// tracking of its own; supply one when integrating with a syncing
+	// client.
+	ChainSync ChainSync
+
 	// Logger
 	Logger *log.Logger
 }
@@ -52,11 +143,40 @@ type ZBFT interface {
 	SetGenesis(blk *bcpb.Block, txs []*bcpb.Tx) *Future
 	// Submits message to consensus algo
 	Step(msg zbftpb.Message)
+	// StepContext submits msg to the consensus engine without blocking: it
+	// returns ErrNotReady before Start has finished initializing,
+	// ErrRateLimited if msg.From has exceeded its configured rate, and
+	// ErrBusy if the inbox is full. ctx cancellation also yields its error.
+	StepContext(ctx context.Context, msg zbftpb.Message) error
+	// Ready reports whether Start has replayed the WAL and begun its
+	// consensus loop and Config.ChainSync reports the node has caught up to
+	// the chain tip, i.e. whether it currently accepts messages via
+	// StepContext. With the default ChainSync (a no-op that always reports
+	// synced), only the first condition applies.
+	Ready() bool
 	// SetTimeout sets the timeout for a given consensus round
 	SetTimeout(d time.Duration)
+	// SetTimePerBlock sets how often the leader proposes a new block even
+	// without pending transactions
+	SetTimePerBlock(d time.Duration)
+	// SetMaxBlockSize sets the maximum serialized size, in bytes, of a
+	// block the leader assembles from the mempool
+	SetMaxBlockSize(n int)
+	// SetMaxTxsPerBlock sets the maximum number of transactions the leader
+	// pulls from the mempool per block
+	SetMaxTxsPerBlock(n int)
+	// SetEmptyBlockPolicy sets whether the leader proposes blocks
+	// containing no transactions to keep the chain live
+	SetEmptyBlockPolicy(p EmptyBlockPolicy)
 	// ProposeTxs proposes Transactions to the ledger.  They are first prepared,
-	// added to a block then proposes to be added to the ledger
+	// added to a block then proposes to be added to the ledger. It blocks
+	// until the preparer accepts the batch; use ProposeTxsContext to bound
+	// that wait.
 	ProposeTxs(txs []*bcpb.Tx) *Future
+	// ProposeTxsContext behaves like ProposeTxs, but never blocks the
+	// caller indefinitely if the preparer is stalled: it returns ctx's
+	// error if ctx is done, and ErrBusy if txCollect is full.
+	ProposeTxsContext(ctx context.Context, txs []*bcpb.Tx) (*Future, error)
 	// BroadcastMessages returns a read-only channel of messages that need to be
 	// broadcasted to the network
 	BroadcastMessages() <-chan zbftpb.Message
@@ -65,18 +185,86 @@ type ZBFT interface {
 // New instantiates a new zbft instance. It takes a blockchain, finite-state-machine
 // and a keypair as  arguments
 func New(conf *Config) ZBFT {
+	qsize := conf.QueueSize
+	if qsize <= 0 {
+		qsize = defaultQueueSize
+	}
+
+	timePerBlock := conf.TimePerBlock
+	if timePerBlock <= 0 {
+		timePerBlock = defaultTimePerBlock
+	}
+
+	maxBlockSize := conf.MaxBlockSize
+	if maxBlockSize <= 0 {
+		maxBlockSize = defaultMaxBlockSize
+	}
+
+	maxTxsPerBlock := conf.MaxTxsPerBlock
+	if maxTxsPerBlock <= 0 {
+		maxTxsPerBlock = defaultMaxTxsPerBlock
+	}
+
+	mp := conf.Mempool
+	if mp == nil {
+		mp = mempool.New(&mempool.Config{
+			TxStore:  conf.Blockchain,
+			Policies: mempool.DefaultPolicies(conf.Blockchain),
+		})
+	}
+
+	tr := conf.Transport
+	if tr == nil {
+		tr = transport.NewChanTransport(qsize)
+	}
+
+	metrics := conf.Metrics
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+
+	bcn := conf.Beacon
+	if bcn == nil {
+		bcn = beacon.NopBeacon{}
+	}
+
+	w := conf.WAL
+	if w == nil {
+		w = wal.NewMemWAL()
+	}
+
+	cs := conf.ChainSync
+	if cs == nil {
+		cs = nopChainSync{}
+	}
+
 	z := &zbft{
-		bc:           conf.Blockchain,
-		hasher:       conf.Blockchain.Hasher().Clone(),
-		kp:           conf.KeyPair,
-		roundTimeout: defaultRoundTimeout,
-		msgBcast:     make(chan zbftpb.Message, 16),
-		msgIn:        make(chan zbftpb.Message, 16),
-		txCollect:    make(chan []*bcpb.Tx, 16),
-		exec:         make(chan *execBlock, 16),
-		confCh:       make(chan configChange, 8),
-		fsm:          conf.FSM,
-		log:          conf.Logger,
+		bc:               conf.Blockchain,
+		hasher:           conf.Blockchain.Hasher().Clone(),
+		kp:               conf.KeyPair,
+		roundTimeout:     defaultRoundTimeout,
+		msgBcast:         make(chan zbftpb.Message, qsize),
+		msgIn:            make(chan zbftpb.Message, qsize),
+		txCollect:        make(chan []*bcpb.Tx, qsize),
+		exec:             make(chan *execBlock, qsize),
+		confCh:           make(chan configChange, 8),
+		mempool:          mp,
+		transport:        tr,
+		metrics:          metrics,
+		rateLimiter:      newPeerRateLimiter(conf.PeerRateLimit, conf.PeerRateWindow),
+		beacon:           bcn,
+		validators:       conf.Validators,
+		blockTimer:       time.NewTimer(timePerBlock),
+		wal:              w,
+		chainSync:        cs,
+		dedup:            wal.NewDedupCache(),
+		roundLog:         newRoundLog(),
+		timePerBlock:     int64(timePerBlock),
+		maxBlockSize:     int64(maxBlockSize),
+		maxTxsPerBlock:   int64(maxTxsPerBlock),
+		emptyBlockPolicy: int32(conf.EmptyBlockPolicy),
+		fsm:              conf.FSM,
+		log:              conf.Logger,
 	}
 
 	z.bc.SetBlockValidator(defaultBlockValidator)
@@ -94,6 +282,30 @@ func (z *zbft) SetTimeout(d time.Duration) {
 	z.confCh <- configChange{typ: confChangeTimeout, data: d}
 }
 
+// SetTimePerBlock sets how often the leader proposes a new block even
+// without pending transactions.
+func (z *zbft) SetTimePerBlock(d time.Duration) {
+	z.confCh <- configChange{typ: confChangeTimePerBlock, data: d}
+}
+
+// SetMaxBlockSize sets the maximum serialized size, in bytes, of a block
+// the leader assembles from the mempool.
+func (z *zbft) SetMaxBlockSize(n int) {
+	z.confCh <- configChange{typ: confChangeMaxBlockSize, data: n}
+}
+
+// SetMaxTxsPerBlock sets the maximum number of transactions the leader
+// pulls from the mempool per block.
+func (z *zbft) SetMaxTxsPerBlock(n int) {
+	z.confCh <- configChange{typ: confChangeMaxTxsPerBlock, data: n}
+}
+
+// SetEmptyBlockPolicy sets whether the leader proposes blocks containing no
+// transactions to keep the chain live.
+func (z *zbft) SetEmptyBlockPolicy(p EmptyBlockPolicy) {
+	z.confCh <- configChange{typ: confChangeEmptyBlockPolicy, data: p}
+}
+
 // SetGenesis broadcasts the given block to the network to bootstrap the ledger.
 // Checking the existence of the previous block and the prepare phase are
 // skipped for the genesis block
@@ -107,6 +319,10 @@ func (z *zbft) SetGenesis(blk *bcpb.Block, txs []*bcpb.Tx) *Future {
 		From:  z.kp.PublicKey,
 	}
 
+	if err := z.wal.Append(msg); err != nil {
+		z.log.Printf("[ERROR] failed appending to WAL: %v", err)
+	}
+
 	z.msgIn <- msg
 	z.broadcast(msg)
 
@@ -115,18 +331,311 @@ func (z *zbft) SetGenesis(blk *bcpb.Block, txs []*bcpb.Tx) *Future {
 
 // Step submits the message to the concensus engine
 func (z *zbft) Step(msg zbftpb.Message) {
+	if z.dedup.Seen(msg) {
+		return
+	}
+
+	if err := z.validateProposal(msg); err != nil {
+		z.log.Printf("[WARN] dropping proposal: %v", err)
+		return
+	}
+
+	if z.needsRecovery(msg) {
+		z.sendRecoveryRequest(msg)
+	}
+
+	if shouldPersist(msg.Type) {
+		if err := z.wal.Append(msg); err != nil {
+			z.log.Printf("[ERROR] failed appending to WAL: %v", err)
+		}
+	}
+
+	if msg.Type == zbftpb.Message_RECOVERY_REQUEST {
+		z.handleRecoveryRequest(msg)
+		return
+	}
+
+	z.roundLog.Record(msg)
+
 	z.msgIn <- msg
 }
 
+// needsRecovery reports whether msg is a COMMIT for a round this node has no
+// recorded PREPARE for, meaning it missed that round's proposal and should
+// ask msg's sender to fill it in via sendRecoveryRequest rather than
+// silently applying a commit it can't otherwise make sense of.
+func (z *zbft) needsRecovery(msg zbftpb.Message) bool {
+	return msg.Type == zbftpb.Message_COMMIT && !z.roundLog.HasPrepare(msg.Height, msg.Round)
+}
+
+// shouldPersist reports whether msg belongs in the WAL. RECOVERY_REQUEST and
+// RECOVERY_MESSAGE are transient point-to-point traffic, not round state a
+// restart needs to resume, so replayWAL never has to special-case them.
+func shouldPersist(typ zbftpb.Message_Type) bool {
+	return typ != zbftpb.Message_RECOVERY_REQUEST && typ != zbftpb.Message_RECOVERY_MESSAGE
+}
+
+// Ready reports whether Start has finished initializing the select loop and
+// Config.ChainSync reports the node has caught up to the chain tip.
+func (z *zbft) Ready() bool {
+	return atomic.LoadInt32(&z.ready) == 1 && z.chainSync.Synced()
+}
+
+// StepContext submits msg to the consensus engine without blocking the
+// caller. Unlike Step, it never blocks on a full inbox: it rejects the
+// message with ErrNotReady, ErrRateLimited or ErrBusy instead.
+func (z *zbft) StepContext(ctx context.Context, msg zbftpb.Message) error {
+	if !z.Ready() {
+		z.metrics.IncMsgsDropped("not_ready")
+		return ErrNotReady
+	}
+
+	if msg.From != nil && !z.rateLimiter.Allow(msg.From, time.Now()) {
+		z.metrics.IncMsgsDropped("rate_limited")
+		return ErrRateLimited
+	}
+
+	if z.dedup.Seen(msg) {
+		return nil
+	}
+
+	if err := z.validateProposal(msg); err != nil {
+		z.metrics.IncMsgsDropped("bad_proposer")
+		return fmt.Errorf("%w: %v", ErrInvalidProposer, err)
+	}
+
+	if z.needsRecovery(msg) {
+		z.sendRecoveryRequest(msg)
+	}
+
+	if msg.Type == zbftpb.Message_RECOVERY_REQUEST {
+		z.handleRecoveryRequest(msg)
+		return nil
+	}
+
+	z.metrics.SetQueueDepth("msg_in", len(z.msgIn))
+
+	select {
+	case z.msgIn <- msg:
+		z.roundLog.Record(msg)
+		if shouldPersist(msg.Type) {
+			if err := z.wal.Append(msg); err != nil {
+				z.log.Printf("[ERROR] failed appending to WAL: %v", err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		z.metrics.IncMsgsDropped("busy")
+		return ErrBusy
+	}
+}
+
+// pumpTransportTimeout is the deadline of the context pumpTransport passes
+// to StepContext. StepContext does not wait on a full inbox, so a message
+// that finds it full is dropped at once with ErrBusy.
+const pumpTransportTimeout = 2 * time.Second
+
+// pumpTransport forwards every message arriving on the configured
+// Transport into the consensus engine via StepContext, so a real network
+// transport (libp2p, gRPC, ...) feeds the engine without the caller having
+// to drain it manually. Unlike Step, StepContext never blocks on a full
+// inbox, so a single misbehaving peer flooding the transport cannot stall
+// delivery for the rest of the network; messages rejected with ErrBusy,
+// ErrNotReady or ErrRateLimited are dropped and logged rather than
+// backpressuring the transport.
+func (z *zbft) pumpTransport() {
+	for msg := range z.transport.Receive() {
+		ctx, cancel := context.WithTimeout(context.Background(), pumpTransportTimeout)
+		err := z.StepContext(ctx, msg)
+		cancel()
+
+		if err != nil {
+			z.log.Printf("[WARN] dropping transport message: %v", err)
+		}
+	}
+}
+
+// ProposeTxs admits txs into the mempool and signals the preparer with the
+// current set of verified transactions. Admission policies (size limits,
+// per-sender rate limits, dependency ordering, the recently-committed cache)
+// run per transaction, so a single rejected tx no longer blocks the rest of
+// the batch. The batch handed to the preparer is further capped at
+// maxTxsPerBlock transactions and maxBlockSize serialized bytes, whichever
+// is reached first. It blocks until the preparer accepts the batch; use
+// ProposeTxsContext to bound that wait.
 func (z *zbft) ProposeTxs(txs []*bcpb.Tx) *Future {
-	fut := z.futs.addTxsActive(txs)
-	z.txCollect <- txs
+	fut, batch := z.admitTxs(txs)
+
+	z.metrics.SetQueueDepth("tx_collect", len(z.txCollect))
+	z.txCollect <- batch
+
 	return fut
 }
 
+// ProposeTxsContext behaves like ProposeTxs, but never blocks the caller
+// indefinitely if txCollect is full because the preparer is stalled: it
+// returns ctx's error if ctx is done, and ErrBusy at once if txCollect is
+// full.
+func (z *zbft) ProposeTxsContext(ctx context.Context, txs []*bcpb.Tx) (*Future, error) {
+	fut, batch := z.admitTxs(txs)
+
+	z.metrics.SetQueueDepth("tx_collect", len(z.txCollect))
+
+	select {
+	case z.txCollect <- batch:
+		return fut, nil
+	case <-ctx.Done():
+		return fut, ctx.Err()
+	default:
+		return fut, ErrBusy
+	}
+}
+
+// admitTxs is the mempool-admission logic shared by ProposeTxs and
+// ProposeTxsContext: it adds txs to the mempool and assembles the batch of
+// verified transactions to hand the preparer, leaving only how that batch
+// is sent on txCollect to the caller.
+func (z *zbft) admitTxs(txs []*bcpb.Tx) (*Future, []*bcpb.Tx) {
+	fut := z.futs.addTxsActive(txs)
+
+	for _, tx := range txs {
+		if err := z.mempool.Add(tx); err != nil {
+			z.log.Printf("[WARN] tx rejected by mempool: %v", err)
+		}
+	}
+
+	maxTxsPerBlock := int(atomic.LoadInt64(&z.maxTxsPerBlock))
+	maxBlockSize := int(atomic.LoadInt64(&z.maxBlockSize))
+
+	return fut, boundTxsBySize(z.mempool.GetVerified(maxTxsPerBlock), maxBlockSize)
+}
+
+// boundTxsBySize truncates txs to the longest prefix whose cumulative
+// serialized size does not exceed maxSize, so a custom Config.Mempool that
+// doesn't itself enforce a block size limit can't hand the leader a block
+// larger than configured. maxSize <= 0 means unbounded.
+func boundTxsBySize(txs []*bcpb.Tx, maxSize int) []*bcpb.Tx {
+	if maxSize <= 0 {
+		return txs
+	}
+
+	total := 0
+	for i, tx := range txs {
+		total += tx.Size()
+		if total > maxSize {
+			return txs[:i]
+		}
+	}
+
+	return txs
+}
+
+// sendRecoveryRequest asks msg's sender for everything it has collected for
+// msg's height/round, rather than silently dropping a message this node has
+// no local state for. The reply is unicast back via the transport, not
+// broadcast.
+func (z *zbft) sendRecoveryRequest(msg zbftpb.Message) {
+	req := zbftpb.Message{
+		Type:   zbftpb.Message_RECOVERY_REQUEST,
+		Height: msg.Height,
+		Round:  msg.Round,
+		From:   z.kp.PublicKey,
+		RecoveryRequest: &zbftpb.RecoveryRequest{
+			Height: msg.Height,
+			Round:  msg.Round,
+			From:   z.kp.PublicKey,
+		},
+	}
+
+	if err := z.transport.Send(msg.From, req); err != nil {
+		z.log.Printf("[ERROR] failed sending recovery request: %v", err)
+	}
+}
+
+// handleRecoveryRequest answers req with everything this node has collected
+// for the requested height/round, unicast back to the requester via the
+// transport. It is the receiving half of sendRecoveryRequest.
+func (z *zbft) handleRecoveryRequest(req zbftpb.Message) {
+	preparations, commits := z.roundLog.Collect(req.Height, req.Round)
+
+	reply := zbftpb.Message{
+		Type:   zbftpb.Message_RECOVERY_MESSAGE,
+		Height: req.Height,
+		Round:  req.Round,
+		From:   z.kp.PublicKey,
+		RecoveryMessage: &zbftpb.RecoveryMessage{
+			Height:       req.Height,
+			Round:        req.Round,
+			From:         z.kp.PublicKey,
+			Preparations: preparations,
+			Commits:      commits,
+		},
+	}
+
+	if err := z.transport.Send(req.From, reply); err != nil {
+		z.log.Printf("[ERROR] failed sending recovery message: %v", err)
+	}
+}
+
+// broadcast sends msg to every peer via the configured Transport.
+func (z *zbft) broadcast(msg zbftpb.Message) {
+	if ct, ok := z.transport.(*transport.ChanTransport); ok {
+		z.metrics.SetQueueDepth("msg_bcast", len(ct.Out()))
+	}
+
+	if err := z.transport.Broadcast(msg); err != nil {
+		z.log.Printf("[ERROR] failed broadcasting message: %v", err)
+	}
+}
+
+// removeCommittedTxs evicts the given txs from the mempool once their
+// containing block has been committed by the executor, so they are no
+// longer offered to the leader and cannot be re-admitted. It also truncates
+// the WAL and dedup cache up to height, since messages below a committed
+// height will never need to be replayed again.
+func (z *zbft) removeCommittedTxs(height uint64, txs []*bcpb.Tx) {
+	z.mempool.RemoveCommitted(txs)
+
+	if err := z.wal.TruncateBefore(height); err != nil {
+		z.log.Printf("[ERROR] failed truncating WAL: %v", err)
+	}
+	z.dedup.ForgetBefore(height)
+	z.roundLog.ForgetBefore(height)
+
+	atomic.StoreUint64(&z.height, height+1)
+	atomic.StoreUint64(&z.round, 0)
+}
+
+// replayWAL re-processes every message left in the WAL by truncation,
+// reconstructing the in-flight round state for the current height/round
+// before Start begins accepting new messages.
+func (z *zbft) replayWAL() {
+	msgs, err := z.wal.Replay()
+	if err != nil {
+		z.log.Printf("[ERROR] failed replaying WAL: %v", err)
+		return
+	}
+
+	for _, msg := range msgs {
+		if z.dedup.Seen(msg) {
+			continue
+		}
+		z.roundLog.Record(msg)
+		z.handleMessage(msg)
+	}
+}
+
 // BroadcastMessages returns a read-only channel of messages that need to be
-// broadcasted to the network
+// broadcasted to the network. It is backed by the configured Transport's
+// outbound queue, so it is only populated when the default in-process
+// *transport.ChanTransport is in use; transports that talk to a real
+// network send messages directly and have nothing to drain here.
 func (z *zbft) BroadcastMessages() <-chan zbftpb.Message {
+	if ct, ok := z.transport.(*transport.ChanTransport); ok {
+		return ct.Out()
+	}
 	return z.msgBcast
 }
 
@@ -134,14 +643,19 @@ func (z *zbft) BroadcastMessages() <-chan zbftpb.Message {
 // on initialization to allow loading of contract library before starting,
 func (z *zbft) Start() {
 
+	z.replayWAL()
+
 	go z.startExecing()
+	go z.pumpTransport()
+
+	atomic.StoreInt32(&z.ready, 1)
 
 	for {
 
 		select {
 
 		case txs := <-z.txq:
-			z.handleReadyTxs(txs)
+			z.handleReadyTxs(txs, false)
 
 		case msg := <-z.msgIn:
 			z.handleMessage(msg)
@@ -149,6 +663,9 @@ func (z *zbft) Start() {
 		case <-z.timer.C:
 			z.handleErrorAndReset(errTimedOut)
 
+		case <-z.blockTimer.C:
+			z.handleReadyTxs(nil, true)
+
 		case cch := <-z.confCh:
 			z.handleConfigChange(cch)
 
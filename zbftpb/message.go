@@ -0,0 +1,43 @@
+// Package zbftpb contains the wire types exchanged between zbft consensus
+// participants. Definitions are hand-written to follow zbftpb.proto field
+// for field, except that From is a *keypair.PublicKey here where the proto
+// carries bytes; they are not generated from the proto file.
+package zbftpb
+
+import (
+	"github.com/hexablock/blockchain/bcpb"
+	"github.com/hexablock/blockchain/keypair"
+)
+
+// Message_Type enumerates the kind of payload carried by a Message.
+type Message_Type int32
+
+const (
+	Message_BOOTSTRAP Message_Type = iota
+	Message_PREPARE
+	Message_COMMIT
+	Message_CHANGEVIEW
+	Message_RECOVERY_REQUEST
+	Message_RECOVERY_MESSAGE
+)
+
+// Message is the wire envelope exchanged between consensus participants.
+type Message struct {
+	Type   Message_Type
+	From   *keypair.PublicKey
+	Height uint64
+	Round  uint64
+	Block  *bcpb.Block
+	Txs    []*bcpb.Tx
+
+	// RecoveryRequest is set when Type == Message_RECOVERY_REQUEST.
+	RecoveryRequest *RecoveryRequest
+	// RecoveryMessage is set when Type == Message_RECOVERY_MESSAGE.
+	RecoveryMessage *RecoveryMessage
+
+	// BeaconEntry is the randomness beacon entry used to compute the
+	// proposer for this Block, so validators can independently verify the
+	// proposer was selected correctly and reject proposals from the wrong
+	// node.
+	BeaconEntry []byte
+}
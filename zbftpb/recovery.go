@@ -0,0 +1,23 @@
+package zbftpb
+
+import "github.com/hexablock/blockchain/keypair"
+
+// RecoveryRequest is sent by a node that received a message for a
+// height/round it holds no local state for, asking the sender to reply with
+// everything it has collected so far for that round.
+type RecoveryRequest struct {
+	Height uint64
+	Round  uint64
+	From   *keypair.PublicKey
+}
+
+// RecoveryMessage carries the prepare/commit signatures a peer has
+// collected for a height/round so a lagging node can catch up without a
+// full block resync.
+type RecoveryMessage struct {
+	Height       uint64
+	Round        uint64
+	From         *keypair.PublicKey
+	Preparations []Message
+	Commits      []Message
+}